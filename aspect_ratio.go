@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os/exec"
+)
+
+type Stream struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+type VideoInfo struct {
+	Streams []Stream `json:"streams"`
+}
+
+// aspectRatioBucket is a named aspect ratio videos are classified into.
+type aspectRatioBucket struct {
+	name      string
+	ratio     float64
+	tolerance float64
+}
+
+// aspectRatioBuckets are checked in order; the first one within its
+// tolerance of the video's actual ratio wins. Names are safe to use as S3
+// key / file path segments (no slashes or colons).
+//
+// 21-9 gets a wider tolerance than the rest: real "21:9" displays and
+// encodes (e.g. 3840x1600, ~2.400) commonly land 2-3% off the mathematical
+// 21:9 ratio (~2.333), which is outside the default tolerance.
+var aspectRatioBuckets = []aspectRatioBucket{
+	{"16-9", 16.0 / 9.0, aspectRatioTolerance},
+	{"9-16", 9.0 / 16.0, aspectRatioTolerance},
+	{"4-3", 4.0 / 3.0, aspectRatioTolerance},
+	{"1-1", 1.0, aspectRatioTolerance},
+	{"21-9", 21.0 / 9.0, 0.03},
+}
+
+// aspectRatioTolerance is the default tolerance: how far (as a fraction of
+// the bucket's ratio) a video's width/height ratio may be from a bucket and
+// still match it.
+const aspectRatioTolerance = 0.02
+
+// classifyAspectRatio buckets a width/height pair into one of
+// aspectRatioBuckets, falling back to "other", and returns the exact
+// (unbucketed) ratio alongside the bucket name so callers that need the
+// precise value (thumbnail padding, player sizing) don't have to re-derive it.
+func classifyAspectRatio(width, height int) (string, float64) {
+	ratio := float64(width) / float64(height)
+	for _, b := range aspectRatioBuckets {
+		if math.Abs(ratio-b.ratio)/b.ratio <= b.tolerance {
+			return b.name, ratio
+		}
+	}
+	return "other", ratio
+}
+
+// getVideoAspectRatio probes filePath with ffprobe and returns its bucketed
+// aspect ratio name and the exact width/height ratio.
+func getVideoAspectRatio(filePath string) (string, float64, error) {
+	cmd := exec.Command("ffprobe", "-v", "error", "-print_format", "json", "-show_streams", filePath)
+	buffer := bytes.NewBuffer(nil)
+	cmd.Stdout = buffer
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		log.Println("Error:", err)
+		log.Println("Stderr:", stderr.String())
+		return "", 0, err
+	}
+
+	var videoInfo VideoInfo
+	if err := json.Unmarshal(buffer.Bytes(), &videoInfo); err != nil {
+		return "", 0, err
+	}
+
+	if len(videoInfo.Streams) == 0 {
+		return "", 0, fmt.Errorf("no video streams found")
+	}
+
+	width, height := videoInfo.Streams[0].Width, videoInfo.Streams[0].Height
+	if height == 0 {
+		return "", 0, fmt.Errorf("video stream reported zero height")
+	}
+
+	name, ratio := classifyAspectRatio(width, height)
+	return name, ratio, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestClassifyAspectRatio(t *testing.T) {
+	tests := []struct {
+		name           string
+		width, height  int
+		wantBucket     string
+		wantRatioAbout float64
+	}{
+		{"1080p landscape", 1920, 1080, "16-9", 16.0 / 9.0},
+		{"1080p portrait", 1080, 1920, "9-16", 9.0 / 16.0},
+		{"480p", 640, 480, "4-3", 4.0 / 3.0},
+		{"square", 1000, 1000, "1-1", 1.0},
+		{"ultrawide", 3840, 1600, "21-9", 3840.0 / 1600.0},
+		{"unusual resolution", 1000, 333, "other", 1000.0 / 333.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket, ratio := classifyAspectRatio(tt.width, tt.height)
+			if bucket != tt.wantBucket {
+				t.Errorf("classifyAspectRatio(%d, %d) bucket = %q, want %q", tt.width, tt.height, bucket, tt.wantBucket)
+			}
+			const epsilon = 1e-9
+			if diff := ratio - tt.wantRatioAbout; diff > epsilon || diff < -epsilon {
+				t.Errorf("classifyAspectRatio(%d, %d) ratio = %v, want %v", tt.width, tt.height, ratio, tt.wantRatioAbout)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package main
+
+import "github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/filestore"
+
+// purgeCachedURL drops any cached presigned URL for key, if cfg.fileStore
+// supports it. It's a no-op for backends (like DiskFileStore) that don't
+// cache signed URLs in the first place.
+func (cfg *apiConfig) purgeCachedURL(key string) {
+	if purger, ok := cfg.fileStore.(filestore.Purger); ok {
+		purger.Purge(key)
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/auth"
+	"github.com/google/uuid"
+	"github.com/kkdai/youtube/v2"
+)
+
+// handlerIngestYoutube pulls a YouTube video in and runs it through the same
+// faststart/S3 pipeline as a browser upload, as an alternative for videos
+// too long to comfortably upload from a browser.
+func (cfg *apiConfig) handlerIngestYoutube(w http.ResponseWriter, r *http.Request) {
+	videoIDString := r.PathValue("videoID")
+	videoID, err := uuid.Parse(videoIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	var params struct {
+		YoutubeID string `json:"youtube_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't decode request body", err)
+		return
+	}
+
+	video, err := cfg.db.GetVideo(videoID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get video", err)
+		return
+	}
+	if video.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "You don't have permission to update this video", nil)
+		return
+	}
+
+	if video.YoutubeID != nil && *video.YoutubeID == params.YoutubeID {
+		video, err = cfg.dbVideoToSignedVideo(video)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't convert video to signed URL", err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, video)
+		return
+	}
+
+	client := youtube.Client{}
+	ytVideo, err := client.GetVideo(params.YoutubeID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't look up YouTube video", err)
+		return
+	}
+
+	format, err := bestProgressiveMP4Format(ytVideo.Formats)
+	if err != nil {
+		respondWithError(w, http.StatusUnprocessableEntity, "Couldn't find a usable stream", err)
+		return
+	}
+
+	stream, _, err := client.GetStream(ytVideo, format)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't open YouTube stream", err)
+		return
+	}
+	defer stream.Close()
+
+	if video.VideoURL != nil {
+		cfg.purgeCachedURL(*video.VideoURL)
+	}
+
+	videoKey, err := cfg.ingestYoutubeStream(r.Context(), stream, format)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't ingest YouTube video", err)
+		return
+	}
+
+	if video.ThumbnailURL == nil {
+		if thumbnailKey, err := cfg.downloadAndStoreYoutubeThumbnail(r.Context(), ytVideo); err != nil {
+			log.Println("Couldn't store YouTube thumbnail:", err)
+		} else {
+			video.ThumbnailURL = &thumbnailKey
+		}
+	}
+
+	video.YoutubeID = &params.YoutubeID
+	video.Title = ytVideo.Title
+	video.Description = ytVideo.Description
+	video.VideoURL = &videoKey
+	video.UpdatedAt = time.Now()
+	if err := cfg.db.UpdateVideo(video); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update video", err)
+		return
+	}
+
+	video, err = cfg.dbVideoToSignedVideo(video)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't convert video to signed URL", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, video)
+}
+
+// bestProgressiveMP4Format picks the highest-bitrate MP4 format that carries
+// both video and audio, so the result can be faststart-processed and served
+// without needing to mux separate audio/video streams back together.
+func bestProgressiveMP4Format(formats youtube.FormatList) (*youtube.Format, error) {
+	var best *youtube.Format
+	for i := range formats {
+		f := &formats[i]
+		if f.AudioChannels == 0 || !strings.HasPrefix(f.MimeType, "video/mp4") {
+			continue
+		}
+		if best == nil || f.Bitrate > best.Bitrate {
+			best = f
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no progressive video/mp4 format with audio found")
+	}
+	return best, nil
+}
+
+// ingestYoutubeStream pipes stream through ffmpeg's faststart remux and
+// straight into the file store, without ever buffering the whole video
+// locally, and returns the key it was stored under.
+func (cfg *apiConfig) ingestYoutubeStream(ctx context.Context, stream io.Reader, format *youtube.Format) (string, error) {
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-i", "pipe:0", "-c", "copy", "-movflags", "faststart", "-f", "mp4", "pipe:1")
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", fmt.Errorf("couldn't open ffmpeg stdout: %w", err)
+	}
+	cmd.Stdin = stream
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("couldn't start ffmpeg: %w", err)
+	}
+
+	progress := NewProgressReader(stdout, int64(format.ContentLength), nil)
+
+	aspect_prefix, _ := classifyAspectRatio(format.Width, format.Height)
+	videoKey := fmt.Sprintf("%s/%s.mp4", aspect_prefix, uuid.NewString())
+
+	_, putErr := cfg.fileStore.Put(ctx, videoKey, progress, "video/mp4")
+
+	if err := cmd.Wait(); err != nil {
+		log.Println("ffmpeg stderr:", stderr.String())
+		return "", fmt.Errorf("ffmpeg remux failed: %w", err)
+	}
+	if putErr != nil {
+		return "", fmt.Errorf("couldn't upload remuxed video: %w", putErr)
+	}
+
+	return videoKey, nil
+}
+
+// downloadAndStoreYoutubeThumbnail fetches the highest-resolution thumbnail
+// YouTube reports for ytVideo and stores it through the file store.
+func (cfg *apiConfig) downloadAndStoreYoutubeThumbnail(ctx context.Context, ytVideo *youtube.Video) (string, error) {
+	if len(ytVideo.Thumbnails) == 0 {
+		return "", fmt.Errorf("video has no thumbnails")
+	}
+	best := ytVideo.Thumbnails[0]
+	for _, t := range ytVideo.Thumbnails {
+		if t.Width > best.Width {
+			best = t
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, best.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("thumbnail request returned %s", resp.Status)
+	}
+
+	thumbnailKey := fmt.Sprintf("%s.jpg", uuid.NewString())
+	if _, err := cfg.fileStore.Put(ctx, thumbnailKey, resp.Body, "image/jpeg"); err != nil {
+		return "", err
+	}
+	return thumbnailKey, nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/auth"
+	"github.com/google/uuid"
+)
+
+// handlerRegenerateThumbnail re-derives a video's poster frame at a
+// caller-chosen timestamp, for when the automatically generated one (from
+// handlerUploadVideo) isn't the frame the user wants.
+func (cfg *apiConfig) handlerRegenerateThumbnail(w http.ResponseWriter, r *http.Request) {
+	videoIDString := r.PathValue("videoID")
+	videoID, err := uuid.Parse(videoIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	video, err := cfg.db.GetVideo(videoID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get video", err)
+		return
+	}
+	if video.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "You don't have permission to update this video", nil)
+		return
+	}
+	if video.VideoURL == nil {
+		respondWithError(w, http.StatusBadRequest, "Video has no uploaded file to take a thumbnail from", nil)
+		return
+	}
+
+	timestamp := defaultThumbnailTimestamp
+	if t := r.URL.Query().Get("t"); t != "" {
+		seconds, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid t query parameter", err)
+			return
+		}
+		timestamp = fmt.Sprintf("%.3f", seconds)
+	}
+
+	videoObject, err := cfg.fileStore.Get(r.Context(), *video.VideoURL)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't read video", err)
+		return
+	}
+	defer videoObject.Close()
+
+	temp_file, err := os.CreateTemp("", "tubely-thumbnail-source.mp4")
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create temp file", err)
+		return
+	}
+	defer os.Remove(temp_file.Name())
+	defer temp_file.Close()
+
+	if _, err := io.Copy(temp_file, videoObject); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't download video", err)
+		return
+	}
+
+	if video.ThumbnailURL != nil {
+		_ = cfg.fileStore.Delete(r.Context(), *video.ThumbnailURL)
+	}
+
+	thumbnailKey, err := cfg.generateAndStoreThumbnail(r.Context(), temp_file.Name(), timestamp)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't generate thumbnail", err)
+		return
+	}
+
+	video.ThumbnailURL = &thumbnailKey
+	video.UpdatedAt = time.Now()
+	if err := cfg.db.UpdateVideo(video); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update video", err)
+		return
+	}
+
+	video, err = cfg.dbVideoToSignedVideo(video)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't convert video to signed URL", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, video)
+}
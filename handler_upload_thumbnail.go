@@ -4,11 +4,8 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
-	"io"
 	"mime"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -68,24 +65,16 @@ func (cfg *apiConfig) handlerUploadThumbnail(w http.ResponseWriter, r *http.Requ
 
 	fprefix := base64.RawURLEncoding.EncodeToString(rand_bytes)
 
-	thumbnail_path := filepath.Join(cfg.assetsRoot, fmt.Sprintf("%s.%s", fprefix, file_ext))
-	tf, err := os.Create(thumbnail_path)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't create file", err)
-		return
-	}
-	defer tf.Close()
+	thumbnailKey := fmt.Sprintf("%s.%s", fprefix, file_ext)
 
-	fmt.Println("Saving thumbnail to", thumbnail_path)
+	fmt.Println("Saving thumbnail", thumbnailKey)
 
-	_, err = io.Copy(tf, file)
+	_, err = cfg.fileStore.Put(r.Context(), thumbnailKey, file, mediaType)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't copy file", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't store thumbnail", err)
 		return
 	}
 
-	dataUrl := fmt.Sprintf("http://localhost:%s/%s", cfg.port, thumbnail_path)
-
 	video, err := cfg.db.GetVideo(videoID)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Couldn't get video", err)
@@ -97,7 +86,7 @@ func (cfg *apiConfig) handlerUploadThumbnail(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	video.ThumbnailURL = &dataUrl
+	video.ThumbnailURL = &thumbnailKey
 	video.UpdatedAt = time.Now()
 	err = cfg.db.UpdateVideo(video)
 	if err != nil {
@@ -105,5 +94,11 @@ func (cfg *apiConfig) handlerUploadThumbnail(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	video, err = cfg.dbVideoToSignedVideo(video)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't convert video to signed URL", err)
+		return
+	}
+
 	respondWithJSON(w, http.StatusOK, video)
 }
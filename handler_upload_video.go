@@ -5,7 +5,6 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -16,8 +15,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/auth"
 	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/database"
 	"github.com/google/uuid"
@@ -45,6 +42,26 @@ func (cfg *apiConfig) handlerUploadVideo(w http.ResponseWriter, r *http.Request)
 
 	fmt.Println("uploading video", videoID, "by user", userID)
 
+	uploadID := uuid.NewString()
+	session := cfg.uploadSessions.create(uploadID, userID)
+	defer session.finish()
+
+	// Flush the uploadID to the client now: the receive/process/upload
+	// pipeline below can run well past the progress session's TTL, so the
+	// client needs it immediately to start polling
+	// /api/video_upload/{uploadID}/progress, not after the whole thing
+	// finishes. Once this WriteHeader lands, the status code is locked in at
+	// 200 - a later failure only writes its error body onto this response;
+	// the progress stream carries no error event.
+	w.Header().Set("X-Upload-Id", uploadID)
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondWithError(w, http.StatusInternalServerError, "Streaming unsupported", nil)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
 	// TODO: implement the upload here
 	r.ParseMultipartForm(10 << 30) // 1 GB limit
 	file, file_header, err := r.FormFile("video")
@@ -73,7 +90,11 @@ func (cfg *apiConfig) handlerUploadVideo(w http.ResponseWriter, r *http.Request)
 
 	defer os.Remove(temp_file.Name())
 	defer temp_file.Close()
-	_, err = io.Copy(temp_file, file)
+
+	receiving := NewProgressReader(file, file_header.Size, func(read, total int64) {
+		session.report(stageReceiving, read, total)
+	})
+	_, err = io.Copy(temp_file, receiving)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Couldn't copy file", err)
 		return
@@ -90,12 +111,14 @@ func (cfg *apiConfig) handlerUploadVideo(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Process the video for fast start
+	session.report(stageProcessing, 0, file_header.Size)
 	processedFilePath, err := processVideoForFastStart(temp_file.Name())
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Couldn't process video", err)
 		return
 	}
 	defer os.Remove(processedFilePath)
+	session.report(stageProcessing, file_header.Size, file_header.Size)
 
 	// open processed file
 	processedFile, err := os.Open(processedFilePath)
@@ -103,8 +126,13 @@ func (cfg *apiConfig) handlerUploadVideo(w http.ResponseWriter, r *http.Request)
 		respondWithError(w, http.StatusInternalServerError, "Couldn't open processed file", err)
 		return
 	}
+	processedFileInfo, err := processedFile.Stat()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't stat processed file", err)
+		return
+	}
 
-	aspect_prefix, err := getVideoAspectRatio(temp_file.Name())
+	aspect_prefix, _, err := getVideoAspectRatio(temp_file.Name())
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Couldn't get video aspect ratio", err)
 		return
@@ -112,21 +140,17 @@ func (cfg *apiConfig) handlerUploadVideo(w http.ResponseWriter, r *http.Request)
 
 	fn := base64.RawURLEncoding.EncodeToString(rand_bytes)
 
-	s3Key := fmt.Sprintf("%s/%s.%s", aspect_prefix, fn, file_ext)
-
-	dataUrl := fmt.Sprintf("%s,%s", cfg.s3Bucket, s3Key)
+	videoKey := fmt.Sprintf("%s/%s.%s", aspect_prefix, fn, file_ext)
 
-	_, err = cfg.s3Client.PutObject(r.Context(), &s3.PutObjectInput{
-		Bucket:      aws.String(cfg.s3Bucket),
-		Key:         aws.String(s3Key),
-		Body:        processedFile,
-		ContentType: aws.String(mediaType),
+	uploading := NewProgressReader(processedFile, processedFileInfo.Size(), func(read, total int64) {
+		session.report(stageUploading, read, total)
 	})
+	_, err = cfg.fileStore.Put(r.Context(), videoKey, uploading, mediaType)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Couldn't upload file", err)
 		return
 	}
-	fmt.Println("Uploaded video to S3")
+	fmt.Println("Uploaded video to", videoKey)
 
 	video, err := cfg.db.GetVideo(videoID)
 	if err != nil {
@@ -139,7 +163,20 @@ func (cfg *apiConfig) handlerUploadVideo(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	video.VideoURL = &dataUrl
+	if video.VideoURL != nil {
+		cfg.purgeCachedURL(*video.VideoURL)
+	}
+
+	if video.ThumbnailURL == nil {
+		thumbnailKey, err := cfg.generateAndStoreThumbnail(r.Context(), processedFilePath, defaultThumbnailTimestamp)
+		if err != nil {
+			log.Println("Couldn't generate thumbnail:", err)
+		} else {
+			video.ThumbnailURL = &thumbnailKey
+		}
+	}
+
+	video.VideoURL = &videoKey
 
 	video.UpdatedAt = time.Now()
 	err = cfg.db.UpdateVideo(video)
@@ -157,54 +194,6 @@ func (cfg *apiConfig) handlerUploadVideo(w http.ResponseWriter, r *http.Request)
 	respondWithJSON(w, http.StatusOK, video)
 }
 
-type Stream struct {
-	Width  int `json:"width"`
-	Height int `json:"height"`
-}
-
-type VideoInfo struct {
-	Streams []Stream `json:"streams"`
-}
-
-func getVideoAspectRatio(filePath string) (string, error) {
-	cmd := exec.Command("ffprobe", "-v", "error", "-print_format", "json", "-show_streams", filePath)
-	buffer := bytes.NewBuffer(nil)
-	cmd.Stdout = buffer
-
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		log.Println("Error:", err)
-		log.Println("Stderr:", stderr.String())
-		return "", err
-	}
-
-	var videoInfo VideoInfo
-	err := json.Unmarshal(buffer.Bytes(), &videoInfo)
-	if err != nil {
-		return "", err
-	}
-
-	var width, height int
-
-	if len(videoInfo.Streams) > 0 {
-		width = videoInfo.Streams[0].Width
-		height = videoInfo.Streams[0].Height
-		// Use width and height as needed
-	} else {
-		return "", fmt.Errorf("no video streams found")
-	}
-
-	if width/height == 16/9 {
-		return "landscape", nil
-	} else if height/width == 16/9 {
-		return "portrait", nil
-	} else {
-		return "other", nil
-	}
-
-}
-
 func processVideoForFastStart(filePath string) (string, error) {
 	outputFilePath := filePath + ".processing"
 	cmd := exec.Command("ffmpeg", "-i", filePath, "-c", "copy", "-movflags", "faststart", "-f", "mp4", outputFilePath)
@@ -220,43 +209,38 @@ func processVideoForFastStart(filePath string) (string, error) {
 	return outputFilePath, nil
 }
 
-func generatePresignedURL(s3Client *s3.Client, bucket, key string, expireTime time.Duration) (string, error) {
-
-	presignClient := s3.NewPresignClient(s3Client)
-
-	r, err := presignClient.PresignGetObject(context.TODO(), &s3.GetObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(key),
-	}, s3.WithPresignExpires(expireTime))
-
-	if err != nil {
-		return "", fmt.Errorf("failed to presign: %w", err)
-	}
-
-	return r.URL, nil
-}
-
+// defaultPresignExpiry is how long a presigned VideoURL/ThumbnailURL stays
+// valid when apiConfig doesn't set presignExpiry explicitly.
+const defaultPresignExpiry = 15 * time.Minute
+
+// dbVideoToSignedVideo resolves video's VideoURL and ThumbnailURL - each a
+// bare store key on the record we keep in the DB - into presigned URLs a
+// client can actually fetch, valid for cfg.presignExpiry. The S3 store's
+// presigned-URL refresh window is not set from apiConfig: main builds the
+// store with the default window. Either field may be
+// nil (a video with no thumbnail yet, or one that's still mid-upload); nil
+// fields pass through untouched.
 func (cfg *apiConfig) dbVideoToSignedVideo(video database.Video) (database.Video, error) {
-	if video.VideoURL == nil {
-		return database.Video{}, fmt.Errorf("video URL is nil")
+	expiry := cfg.presignExpiry
+	if expiry == 0 {
+		expiry = defaultPresignExpiry
 	}
 
-	url_split := strings.Split(*video.VideoURL, ",")
-	if len(url_split) != 2 {
-		return database.Video{}, fmt.Errorf("invalid video URL format: %s", video.VideoURL)
+	if video.VideoURL != nil {
+		presignedURL, err := cfg.fileStore.PresignGet(context.Background(), *video.VideoURL, expiry)
+		if err != nil {
+			return video, fmt.Errorf("failed to generate presigned URL: %w", err)
+		}
+		video.VideoURL = &presignedURL
 	}
 
-	bucket, key := url_split[0], url_split[1]
-
-	// Make sure cfg.s3Client is not nil here
-	if cfg.s3Client == nil {
-		return database.Video{}, fmt.Errorf("s3 client is nil")
+	if video.ThumbnailURL != nil {
+		presignedURL, err := cfg.fileStore.PresignGet(context.Background(), *video.ThumbnailURL, expiry)
+		if err != nil {
+			return video, fmt.Errorf("failed to generate presigned thumbnail URL: %w", err)
+		}
+		video.ThumbnailURL = &presignedURL
 	}
 
-	presignedURL, err := generatePresignedURL(cfg.s3Client, bucket, key, 15*time.Minute)
-	if err != nil {
-		return video, fmt.Errorf("failed to generate presigned URL: %w", err)
-	}
-	video.VideoURL = &presignedURL
 	return video, nil
 }
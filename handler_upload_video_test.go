@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/database"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/filestore"
+)
+
+func TestDbVideoToSignedVideo(t *testing.T) {
+	cfg := &apiConfig{fileStore: filestore.NewMockFileStore()}
+
+	videoKey := "720x1280/abc.mp4"
+	thumbnailKey := "abc.jpg"
+	video := database.Video{
+		VideoURL:     &videoKey,
+		ThumbnailURL: &thumbnailKey,
+	}
+
+	signed, err := cfg.dbVideoToSignedVideo(video)
+	if err != nil {
+		t.Fatalf("dbVideoToSignedVideo returned error: %v", err)
+	}
+
+	wantVideoURL := "https://mock.local/" + videoKey
+	if signed.VideoURL == nil || *signed.VideoURL != wantVideoURL {
+		t.Errorf("VideoURL = %v, want %q", signed.VideoURL, wantVideoURL)
+	}
+
+	wantThumbnailURL := "https://mock.local/" + thumbnailKey
+	if signed.ThumbnailURL == nil || *signed.ThumbnailURL != wantThumbnailURL {
+		t.Errorf("ThumbnailURL = %v, want %q", signed.ThumbnailURL, wantThumbnailURL)
+	}
+}
+
+func TestDbVideoToSignedVideo_NilURLsPassThrough(t *testing.T) {
+	cfg := &apiConfig{fileStore: filestore.NewMockFileStore()}
+
+	signed, err := cfg.dbVideoToSignedVideo(database.Video{})
+	if err != nil {
+		t.Fatalf("dbVideoToSignedVideo returned error: %v", err)
+	}
+	if signed.VideoURL != nil {
+		t.Errorf("VideoURL = %v, want nil", signed.VideoURL)
+	}
+	if signed.ThumbnailURL != nil {
+		t.Errorf("ThumbnailURL = %v, want nil", signed.ThumbnailURL)
+	}
+}
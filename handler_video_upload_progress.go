@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/auth"
+)
+
+// handlerVideoUploadProgress streams Server-Sent Events for an in-flight
+// upload registered under uploadID, until the upload finishes or the client
+// disconnects.
+func (cfg *apiConfig) handlerVideoUploadProgress(w http.ResponseWriter, r *http.Request) {
+	uploadID := r.PathValue("uploadID")
+
+	session, ok := cfg.uploadSessions.get(uploadID)
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Unknown upload ID", nil)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+	if userID != session.userID {
+		respondWithError(w, http.StatusForbidden, "You don't have permission to view this upload's progress", nil)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondWithError(w, http.StatusInternalServerError, "Streaming unsupported", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	writeEvent := func(p uploadProgress) {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+	}
+
+	// Send whatever we already know before waiting on new events.
+	writeEvent(session.latestProgress())
+
+	for {
+		select {
+		case p := <-session.events:
+			writeEvent(p)
+		case <-session.done:
+			writeEvent(session.latestProgress())
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
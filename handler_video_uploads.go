@@ -0,0 +1,326 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/auth"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/filestore"
+	"github.com/google/uuid"
+)
+
+// multipartFileStore asserts that cfg.fileStore also supports the chunked
+// upload operations; DiskFileStore and S3FileStore both implement it.
+func (cfg *apiConfig) multipartFileStore() (filestore.MultipartStore, error) {
+	m, ok := cfg.fileStore.(filestore.MultipartStore)
+	if !ok {
+		return nil, fmt.Errorf("file store does not support multipart uploads")
+	}
+	return m, nil
+}
+
+// handlerCreateVideoUpload begins a chunked upload for an existing video,
+// returning an uploadID the client uses for every subsequent part.
+func (cfg *apiConfig) handlerCreateVideoUpload(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	var params struct {
+		VideoID uuid.UUID `json:"video_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't decode request body", err)
+		return
+	}
+
+	video, err := cfg.db.GetVideo(params.VideoID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get video", err)
+		return
+	}
+	if video.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "You don't have permission to upload this video", nil)
+		return
+	}
+
+	store, err := cfg.multipartFileStore()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't start chunked upload", err)
+		return
+	}
+
+	rawKey := fmt.Sprintf("uploads/raw/%s.mp4", uuid.NewString())
+	storeUploadID, err := store.CreateMultipartUpload(r.Context(), rawKey, "video/mp4")
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create multipart upload", err)
+		return
+	}
+
+	uploadID := cfg.multipartUploads.create(&multipartUpload{
+		videoID:     params.VideoID,
+		userID:      userID,
+		key:         rawKey,
+		storeUpload: storeUploadID,
+		mediaType:   "video/mp4",
+	})
+
+	respondWithJSON(w, http.StatusOK, struct {
+		UploadID string `json:"upload_id"`
+	}{UploadID: uploadID})
+}
+
+// handlerUploadVideoPart streams one chunk straight through to the store's
+// UploadPart, recording its ETag so the part doesn't need to be resent if
+// the client reconnects.
+func (cfg *apiConfig) handlerUploadVideoPart(w http.ResponseWriter, r *http.Request) {
+	uploadID := r.PathValue("uploadID")
+	partNumber, err := strconv.ParseInt(r.PathValue("partNumber"), 10, 32)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid part number", err)
+		return
+	}
+
+	upload, ok := cfg.multipartUploads.get(uploadID)
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Unknown upload ID", nil)
+		return
+	}
+
+	if _, err := cfg.authorizeUploadOwner(r, upload.userID); err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't authorize request", err)
+		return
+	}
+
+	store, err := cfg.multipartFileStore()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't upload part", err)
+		return
+	}
+
+	etag, err := store.UploadPart(r.Context(), upload.key, upload.storeUpload, int32(partNumber), r.Body)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't upload part", err)
+		return
+	}
+
+	if err := cfg.db.CreateUploadPart(uploadID, int32(partNumber), etag); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't record uploaded part", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, struct {
+		ETag string `json:"etag"`
+	}{ETag: etag})
+}
+
+// handlerListVideoUploadParts returns the part numbers already recorded for
+// uploadID, so a resuming client knows which chunks it still needs to send.
+func (cfg *apiConfig) handlerListVideoUploadParts(w http.ResponseWriter, r *http.Request) {
+	uploadID := r.PathValue("uploadID")
+
+	upload, ok := cfg.multipartUploads.get(uploadID)
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Unknown upload ID", nil)
+		return
+	}
+	if _, err := cfg.authorizeUploadOwner(r, upload.userID); err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't authorize request", err)
+		return
+	}
+
+	parts, err := cfg.db.ListUploadParts(uploadID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list uploaded parts", err)
+		return
+	}
+	partNumbers := make([]int32, 0, len(parts))
+	for _, p := range parts {
+		partNumbers = append(partNumbers, p.PartNumber)
+	}
+	sort.Slice(partNumbers, func(i, j int) bool { return partNumbers[i] < partNumbers[j] })
+
+	respondWithJSON(w, http.StatusOK, struct {
+		PartNumbers []int32 `json:"part_numbers"`
+	}{PartNumbers: partNumbers})
+}
+
+// handlerCompleteVideoUpload finishes the multipart upload, then runs the
+// same ffprobe/faststart pipeline handlerUploadVideo uses on a streamed
+// download of the assembled object.
+func (cfg *apiConfig) handlerCompleteVideoUpload(w http.ResponseWriter, r *http.Request) {
+	uploadID := r.PathValue("uploadID")
+
+	upload, ok := cfg.multipartUploads.get(uploadID)
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Unknown upload ID", nil)
+		return
+	}
+	if _, err := cfg.authorizeUploadOwner(r, upload.userID); err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't authorize request", err)
+		return
+	}
+
+	store, err := cfg.multipartFileStore()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't complete upload", err)
+		return
+	}
+
+	defer cfg.multipartUploads.delete(uploadID)
+	defer func() {
+		if err := cfg.db.DeleteUploadParts(uploadID); err != nil {
+			log.Println("Couldn't clean up recorded parts:", err)
+		}
+	}()
+
+	recordedParts, err := cfg.db.ListUploadParts(uploadID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list uploaded parts", err)
+		return
+	}
+	sort.Slice(recordedParts, func(i, j int) bool { return recordedParts[i].PartNumber < recordedParts[j].PartNumber })
+
+	completedParts := make([]filestore.CompletedPart, len(recordedParts))
+	for i, p := range recordedParts {
+		completedParts[i] = filestore.CompletedPart{PartNumber: p.PartNumber, ETag: p.ETag}
+	}
+
+	if _, err := store.CompleteMultipartUpload(r.Context(), upload.key, upload.storeUpload, completedParts); err != nil {
+		if abortErr := store.AbortMultipartUpload(r.Context(), upload.key, upload.storeUpload); abortErr != nil {
+			log.Println("Couldn't abort multipart upload after a failed complete:", abortErr)
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't complete multipart upload", err)
+		return
+	}
+
+	rawObject, err := cfg.fileStore.Get(r.Context(), upload.key)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't read assembled upload", err)
+		return
+	}
+	defer rawObject.Close()
+
+	temp_file, err := os.CreateTemp("", "tubely-upload.mp4")
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create temp file", err)
+		return
+	}
+	defer os.Remove(temp_file.Name())
+	defer temp_file.Close()
+
+	if _, err := io.Copy(temp_file, rawObject); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't download assembled upload", err)
+		return
+	}
+
+	processedFilePath, err := processVideoForFastStart(temp_file.Name())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't process video", err)
+		return
+	}
+	defer os.Remove(processedFilePath)
+
+	processedFile, err := os.Open(processedFilePath)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't open processed file", err)
+		return
+	}
+	defer processedFile.Close()
+
+	aspect_prefix, _, err := getVideoAspectRatio(temp_file.Name())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get video aspect ratio", err)
+		return
+	}
+
+	videoKey := fmt.Sprintf("%s/%s.mp4", aspect_prefix, uuid.NewString())
+	if _, err := cfg.fileStore.Put(r.Context(), videoKey, processedFile, upload.mediaType); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't upload file", err)
+		return
+	}
+	_ = cfg.fileStore.Delete(r.Context(), upload.key)
+
+	video, err := cfg.db.GetVideo(upload.videoID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get video", err)
+		return
+	}
+	video.VideoURL = &videoKey
+	video.UpdatedAt = time.Now()
+	if err := cfg.db.UpdateVideo(video); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update video", err)
+		return
+	}
+
+	video, err = cfg.dbVideoToSignedVideo(video)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't convert video to signed URL", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, video)
+}
+
+// handlerAbortVideoUpload cancels an in-progress chunked upload and releases
+// any parts already stored.
+func (cfg *apiConfig) handlerAbortVideoUpload(w http.ResponseWriter, r *http.Request) {
+	uploadID := r.PathValue("uploadID")
+
+	upload, ok := cfg.multipartUploads.get(uploadID)
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Unknown upload ID", nil)
+		return
+	}
+	if _, err := cfg.authorizeUploadOwner(r, upload.userID); err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't authorize request", err)
+		return
+	}
+
+	store, err := cfg.multipartFileStore()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't abort upload", err)
+		return
+	}
+	if err := store.AbortMultipartUpload(r.Context(), upload.key, upload.storeUpload); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't abort multipart upload", err)
+		return
+	}
+	cfg.multipartUploads.delete(uploadID)
+	if err := cfg.db.DeleteUploadParts(uploadID); err != nil {
+		log.Println("Couldn't clean up recorded parts:", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorizeUploadOwner validates the bearer token on r and checks it belongs
+// to ownerID, the user who started the chunked upload.
+func (cfg *apiConfig) authorizeUploadOwner(r *http.Request, ownerID uuid.UUID) (uuid.UUID, error) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if userID != ownerID {
+		return uuid.Nil, fmt.Errorf("user %s does not own this upload", userID)
+	}
+	return userID, nil
+}
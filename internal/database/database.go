@@ -0,0 +1,126 @@
+// Package database implements a tiny JSON-file-backed store for video
+// metadata. It's not meant to scale past a single process - just enough
+// persistence for local development and the course exercises.
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotExist is returned when a lookup doesn't find a matching record.
+var ErrNotExist = errors.New("record does not exist")
+
+// DB is a JSON file on disk, guarded by a mutex so handlers running on
+// separate goroutines don't race on it.
+type DB struct {
+	path string
+	mu   sync.RWMutex
+}
+
+// dbSchema is the on-disk shape of the JSON file. extra holds any top-level
+// keys this package doesn't model (e.g. tables owned by other parts of the
+// app) verbatim, so loading and re-saving the file never silently drops
+// them - dbSchema only needs to know about the tables it actually reads or
+// writes.
+type dbSchema struct {
+	Videos      map[uuid.UUID]Video     `json:"videos"`
+	UploadParts map[string][]UploadPart `json:"upload_parts"`
+	extra       map[string]json.RawMessage
+}
+
+func (s dbSchema) MarshalJSON() ([]byte, error) {
+	raw := make(map[string]json.RawMessage, len(s.extra)+2)
+	for k, v := range s.extra {
+		raw[k] = v
+	}
+
+	videos, err := json.Marshal(s.Videos)
+	if err != nil {
+		return nil, err
+	}
+	raw["videos"] = videos
+
+	uploadParts, err := json.Marshal(s.UploadParts)
+	if err != nil {
+		return nil, err
+	}
+	raw["upload_parts"] = uploadParts
+
+	return json.Marshal(raw)
+}
+
+func (s *dbSchema) UnmarshalJSON(data []byte) error {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if v, ok := raw["videos"]; ok {
+		if err := json.Unmarshal(v, &s.Videos); err != nil {
+			return err
+		}
+		delete(raw, "videos")
+	}
+	if v, ok := raw["upload_parts"]; ok {
+		if err := json.Unmarshal(v, &s.UploadParts); err != nil {
+			return err
+		}
+		delete(raw, "upload_parts")
+	}
+
+	s.extra = raw
+	return nil
+}
+
+// NewDB opens (creating if necessary) the JSON database file at path.
+func NewDB(path string) (*DB, error) {
+	db := &DB{path: path}
+	if err := db.ensureDB(); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func (db *DB) ensureDB() error {
+	if _, err := os.Stat(db.path); errors.Is(err, os.ErrNotExist) {
+		return db.writeDB(dbSchema{
+			Videos:      map[uuid.UUID]Video{},
+			UploadParts: map[string][]UploadPart{},
+		})
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (db *DB) loadDB() (dbSchema, error) {
+	data, err := os.ReadFile(db.path)
+	if err != nil {
+		return dbSchema{}, err
+	}
+
+	var schema dbSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return dbSchema{}, err
+	}
+	if schema.Videos == nil {
+		schema.Videos = map[uuid.UUID]Video{}
+	}
+	if schema.UploadParts == nil {
+		schema.UploadParts = map[string][]UploadPart{}
+	}
+	return schema, nil
+}
+
+func (db *DB) writeDB(schema dbSchema) error {
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(db.path, data, 0600)
+}
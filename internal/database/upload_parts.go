@@ -0,0 +1,68 @@
+package database
+
+// UploadPart records the ETag S3 (or the disk store) returned for one part
+// of an in-progress chunked upload, keyed by the upload's ID. These records
+// are kept in the same on-disk DB as videos, so they outlive a server
+// restart; the uploadID they are keyed by is only resolvable by the server
+// process that issued it.
+type UploadPart struct {
+	UploadID   string `json:"upload_id"`
+	PartNumber int32  `json:"part_number"`
+	ETag       string `json:"etag"`
+}
+
+// CreateUploadPart records (or overwrites, on resend) the ETag for
+// uploadID/partNumber.
+func (db *DB) CreateUploadPart(uploadID string, partNumber int32, etag string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	data, err := db.loadDB()
+	if err != nil {
+		return err
+	}
+
+	parts := data.UploadParts[uploadID]
+	replaced := false
+	for i, p := range parts {
+		if p.PartNumber == partNumber {
+			parts[i].ETag = etag
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		parts = append(parts, UploadPart{UploadID: uploadID, PartNumber: partNumber, ETag: etag})
+	}
+	data.UploadParts[uploadID] = parts
+
+	return db.writeDB(data)
+}
+
+// ListUploadParts returns the parts recorded for uploadID, in no particular
+// order; callers that care about part order (completing the upload) should
+// sort by PartNumber themselves.
+func (db *DB) ListUploadParts(uploadID string) ([]UploadPart, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	data, err := db.loadDB()
+	if err != nil {
+		return nil, err
+	}
+	return data.UploadParts[uploadID], nil
+}
+
+// DeleteUploadParts forgets every part recorded for uploadID, once the
+// upload has been completed or aborted.
+func (db *DB) DeleteUploadParts(uploadID string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	data, err := db.loadDB()
+	if err != nil {
+		return err
+	}
+	delete(data.UploadParts, uploadID)
+	return db.writeDB(data)
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Video is a single video's metadata record.
+type Video struct {
+	ID           uuid.UUID `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	ThumbnailURL *string   `json:"thumbnail_url"`
+	VideoURL     *string   `json:"video_url"`
+	// YoutubeID is set when a video was ingested from YouTube rather than
+	// uploaded directly, and is used to make re-ingestion idempotent.
+	YoutubeID *string `json:"youtube_id"`
+	CreateVideoParams
+}
+
+// CreateVideoParams are the fields a caller supplies when creating a video;
+// the rest are assigned by the database.
+type CreateVideoParams struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	UserID      uuid.UUID `json:"user_id"`
+}
+
+// CreateVideo inserts a new video record owned by params.UserID.
+func (db *DB) CreateVideo(params CreateVideoParams) (Video, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	data, err := db.loadDB()
+	if err != nil {
+		return Video{}, err
+	}
+
+	video := Video{
+		ID:                uuid.New(),
+		CreatedAt:         time.Now().UTC(),
+		UpdatedAt:         time.Now().UTC(),
+		CreateVideoParams: params,
+	}
+	data.Videos[video.ID] = video
+
+	if err := db.writeDB(data); err != nil {
+		return Video{}, err
+	}
+	return video, nil
+}
+
+// GetVideo returns the video record for id.
+func (db *DB) GetVideo(id uuid.UUID) (Video, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	data, err := db.loadDB()
+	if err != nil {
+		return Video{}, err
+	}
+
+	video, ok := data.Videos[id]
+	if !ok {
+		return Video{}, ErrNotExist
+	}
+	return video, nil
+}
+
+// UpdateVideo persists changes made to video, keyed by its ID.
+func (db *DB) UpdateVideo(video Video) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	data, err := db.loadDB()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := data.Videos[video.ID]; !ok {
+		return ErrNotExist
+	}
+	data.Videos[video.ID] = video
+
+	return db.writeDB(data)
+}
+
+// DeleteVideo removes the video record for id.
+func (db *DB) DeleteVideo(id uuid.UUID) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	data, err := db.loadDB()
+	if err != nil {
+		return err
+	}
+
+	delete(data.Videos, id)
+	return db.writeDB(data)
+}
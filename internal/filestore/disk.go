@@ -0,0 +1,136 @@
+package filestore
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DiskFileStore stores objects under root on the local filesystem and serves
+// them back out from baseURL, so the module can be run in dev without AWS
+// credentials.
+type DiskFileStore struct {
+	root    string
+	baseURL string
+}
+
+// NewDiskFileStore builds a DiskFileStore rooted at root. baseURL is the
+// prefix (e.g. "http://localhost:8091/assets") under which root is served.
+func NewDiskFileStore(root, baseURL string) *DiskFileStore {
+	return &DiskFileStore{root: root, baseURL: baseURL}
+}
+
+// Root returns the local directory objects are stored under, so callers can
+// serve it directly (e.g. with http.FileServer) alongside the API.
+func (d *DiskFileStore) Root() string {
+	return d.root
+}
+
+func (d *DiskFileStore) Put(ctx context.Context, key string, body io.Reader, contentType string) (StoredObject, error) {
+	path := filepath.Join(d.root, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return StoredObject{}, fmt.Errorf("couldn't create directory for %s: %w", key, err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return StoredObject{}, fmt.Errorf("couldn't create file %s: %w", key, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, body); err != nil {
+		return StoredObject{}, fmt.Errorf("couldn't write file %s: %w", key, err)
+	}
+	return StoredObject{Key: key}, nil
+}
+
+// PresignGet ignores ttl: files on disk are served for as long as they exist.
+func (d *DiskFileStore) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return fmt.Sprintf("%s/%s", d.baseURL, key), nil
+}
+
+func (d *DiskFileStore) Delete(ctx context.Context, key string) error {
+	err := os.Remove(filepath.Join(d.root, key))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("couldn't delete file %s: %w", key, err)
+	}
+	return nil
+}
+
+func (d *DiskFileStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(d.root, key))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open file %s: %w", key, err)
+	}
+	return f, nil
+}
+
+// partsDir returns the scratch directory an in-progress multipart upload
+// writes its parts to, under root so it shares the same volume as the final
+// object.
+func (d *DiskFileStore) partsDir(uploadID string) string {
+	return filepath.Join(d.root, ".multipart", uploadID)
+}
+
+func (d *DiskFileStore) CreateMultipartUpload(ctx context.Context, key, contentType string) (string, error) {
+	uploadID := base64.RawURLEncoding.EncodeToString([]byte(key + "#" + fmt.Sprint(time.Now().UnixNano())))
+	if err := os.MkdirAll(d.partsDir(uploadID), 0755); err != nil {
+		return "", fmt.Errorf("couldn't create multipart scratch dir: %w", err)
+	}
+	return uploadID, nil
+}
+
+func (d *DiskFileStore) UploadPart(ctx context.Context, key, uploadID string, partNumber int32, body io.Reader) (string, error) {
+	path := filepath.Join(d.partsDir(uploadID), fmt.Sprintf("%d", partNumber))
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("couldn't create part file: %w", err)
+	}
+	defer f.Close()
+
+	h := md5.New()
+	if _, err := io.Copy(f, io.TeeReader(body, h)); err != nil {
+		return "", fmt.Errorf("couldn't write part file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (d *DiskFileStore) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart) (StoredObject, error) {
+	path := filepath.Join(d.root, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return StoredObject{}, fmt.Errorf("couldn't create directory for %s: %w", key, err)
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return StoredObject{}, fmt.Errorf("couldn't create file %s: %w", key, err)
+	}
+	defer out.Close()
+
+	for _, p := range parts {
+		partPath := filepath.Join(d.partsDir(uploadID), fmt.Sprintf("%d", p.PartNumber))
+		in, err := os.Open(partPath)
+		if err != nil {
+			return StoredObject{}, fmt.Errorf("couldn't open part %d: %w", p.PartNumber, err)
+		}
+		_, err = io.Copy(out, in)
+		in.Close()
+		if err != nil {
+			return StoredObject{}, fmt.Errorf("couldn't assemble part %d: %w", p.PartNumber, err)
+		}
+	}
+
+	os.RemoveAll(d.partsDir(uploadID))
+	return StoredObject{Key: key}, nil
+}
+
+func (d *DiskFileStore) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	if err := os.RemoveAll(d.partsDir(uploadID)); err != nil {
+		return fmt.Errorf("couldn't remove multipart scratch dir: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+)
+
+func TestDiskFileStorePutAndDelete(t *testing.T) {
+	root := t.TempDir()
+	store := NewDiskFileStore(root, "http://localhost:8091/assets")
+
+	ctx := context.Background()
+	obj, err := store.Put(ctx, "landscape/video.mp4", bytes.NewReader([]byte("hello")), "video/mp4")
+	if err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+	if obj.Key != "landscape/video.mp4" {
+		t.Errorf("Key = %q, want %q", obj.Key, "landscape/video.mp4")
+	}
+
+	if _, err := os.Stat(root + "/landscape/video.mp4"); err != nil {
+		t.Fatalf("expected file on disk: %v", err)
+	}
+
+	url, err := store.PresignGet(ctx, "landscape/video.mp4", 0)
+	if err != nil {
+		t.Fatalf("PresignGet returned error: %v", err)
+	}
+	want := "http://localhost:8091/assets/landscape/video.mp4"
+	if url != want {
+		t.Errorf("PresignGet = %q, want %q", url, want)
+	}
+
+	if err := store.Delete(ctx, "landscape/video.mp4"); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	if _, err := os.Stat(root + "/landscape/video.mp4"); !os.IsNotExist(err) {
+		t.Errorf("expected file to be removed, stat err = %v", err)
+	}
+}
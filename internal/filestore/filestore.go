@@ -0,0 +1,62 @@
+// Package filestore provides a small storage abstraction used by the video
+// and thumbnail upload handlers so that handler logic does not need to know
+// whether it's talking to S3 or the local disk.
+package filestore
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// StoredObject describes the result of a successful Put call.
+type StoredObject struct {
+	Key string
+}
+
+// FileStore is implemented by the storage backends that back uploaded
+// thumbnails and videos. Keys are opaque identifiers chosen by the caller;
+// implementations are free to namespace them (under a bucket, a root
+// directory, etc.) however they need to.
+type FileStore interface {
+	// Put writes body under key, returning the StoredObject that callers
+	// should persist (e.g. in the video's VideoURL/ThumbnailURL column).
+	Put(ctx context.Context, key string, body io.Reader, contentType string) (StoredObject, error)
+	// PresignGet returns a URL that can be used to read key for ttl.
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+	// Delete removes key. It is not an error to delete a key that doesn't exist.
+	Delete(ctx context.Context, key string) error
+	// Get opens key for reading.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// Purger is implemented by FileStore backends that cache derived data (like
+// presigned URLs) keyed by object key, so callers can invalidate it when the
+// object changes underneath them.
+type Purger interface {
+	Purge(key string)
+}
+
+// CompletedPart is one part of a finished multipart upload, identified by
+// the part number it was uploaded under and the ETag the store returned.
+type CompletedPart struct {
+	PartNumber int32
+	ETag       string
+}
+
+// MultipartStore is implemented by FileStore backends that can accept an
+// object in separately-uploaded parts instead of a single Put call, so that
+// large uploads can be resumed after a dropped connection.
+type MultipartStore interface {
+	// CreateMultipartUpload begins a multipart upload for key, returning an
+	// opaque upload ID that must be passed to the remaining methods.
+	CreateMultipartUpload(ctx context.Context, key, contentType string) (uploadID string, err error)
+	// UploadPart uploads one part of an in-progress multipart upload,
+	// returning the ETag to record against partNumber.
+	UploadPart(ctx context.Context, key, uploadID string, partNumber int32, body io.Reader) (etag string, err error)
+	// CompleteMultipartUpload finishes the upload, assembling parts in order.
+	CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart) (StoredObject, error)
+	// AbortMultipartUpload cancels an in-progress multipart upload and
+	// releases any parts already uploaded.
+	AbortMultipartUpload(ctx context.Context, key, uploadID string) error
+}
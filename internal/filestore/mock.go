@@ -0,0 +1,51 @@
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// MockFileStore is a minimal in-memory FileStore used by handler tests so
+// they can exercise upload logic without AWS or the local filesystem.
+type MockFileStore struct {
+	Objects map[string][]byte
+
+	PresignGetFunc func(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// NewMockFileStore returns an empty MockFileStore ready for use.
+func NewMockFileStore() *MockFileStore {
+	return &MockFileStore{Objects: make(map[string][]byte)}
+}
+
+func (m *MockFileStore) Put(ctx context.Context, key string, body io.Reader, contentType string) (StoredObject, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return StoredObject{}, err
+	}
+	m.Objects[key] = data
+	return StoredObject{Key: key}, nil
+}
+
+func (m *MockFileStore) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if m.PresignGetFunc != nil {
+		return m.PresignGetFunc(ctx, key, ttl)
+	}
+	return "https://mock.local/" + key, nil
+}
+
+func (m *MockFileStore) Delete(ctx context.Context, key string) error {
+	delete(m.Objects, key)
+	return nil
+}
+
+func (m *MockFileStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := m.Objects[key]
+	if !ok {
+		return nil, fmt.Errorf("no such object: %s", key)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
@@ -0,0 +1,50 @@
+package filestore
+
+import (
+	"sync"
+	"time"
+)
+
+type presignCacheEntry struct {
+	url       string
+	expiresAt time.Time
+}
+
+// presignCache holds recently-signed GET URLs keyed by object key, so a
+// list endpoint re-signing the same handful of videos on every request
+// doesn't re-sign ones whose URL is still comfortably valid.
+type presignCache struct {
+	mu      sync.Mutex
+	entries map[string]presignCacheEntry
+}
+
+func newPresignCache() *presignCache {
+	return &presignCache{entries: make(map[string]presignCacheEntry)}
+}
+
+// get returns the cached URL for key if it won't expire within
+// refreshWindow, so the caller can skip re-signing.
+func (c *presignCache) get(key string, refreshWindow time.Duration) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Until(entry.expiresAt) <= refreshWindow {
+		return "", false
+	}
+	return entry.url, true
+}
+
+func (c *presignCache) set(key, url string, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = presignCacheEntry{url: url, expiresAt: expiresAt}
+}
+
+// purge drops any cached URL for key, e.g. after the underlying object has
+// been re-uploaded or deleted.
+func (c *presignCache) purge(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
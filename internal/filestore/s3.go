@@ -0,0 +1,172 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3PresignAPI is the subset of *s3.PresignClient that S3FileStore needs,
+// narrowed so tests can substitute a mock instead of signing real requests.
+type S3PresignAPI interface {
+	PresignGetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error)
+}
+
+// defaultPresignRefreshWindow is how close to expiry a cached presigned URL
+// may be before S3FileStore re-signs it.
+const defaultPresignRefreshWindow = 2 * time.Minute
+
+// S3FileStore stores objects in a single S3 bucket.
+type S3FileStore struct {
+	client        *s3.Client
+	presignClient S3PresignAPI
+	bucket        string
+
+	presignCache  *presignCache
+	refreshWindow time.Duration
+}
+
+// NewS3FileStore builds an S3FileStore backed by client, scoped to bucket,
+// with the default presigned-URL refresh window.
+func NewS3FileStore(client *s3.Client, bucket string) *S3FileStore {
+	return newS3FileStore(client, s3.NewPresignClient(client), bucket, defaultPresignRefreshWindow)
+}
+
+// NewS3FileStoreWithRefreshWindow is like NewS3FileStore but lets the
+// refresh window be configured, e.g. from apiConfig.
+func NewS3FileStoreWithRefreshWindow(client *s3.Client, bucket string, refreshWindow time.Duration) *S3FileStore {
+	return newS3FileStore(client, s3.NewPresignClient(client), bucket, refreshWindow)
+}
+
+func newS3FileStore(client *s3.Client, presignClient S3PresignAPI, bucket string, refreshWindow time.Duration) *S3FileStore {
+	return &S3FileStore{
+		client:        client,
+		presignClient: presignClient,
+		bucket:        bucket,
+		presignCache:  newPresignCache(),
+		refreshWindow: refreshWindow,
+	}
+}
+
+func (s *S3FileStore) Put(ctx context.Context, key string, body io.Reader, contentType string) (StoredObject, error) {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return StoredObject{}, fmt.Errorf("couldn't put object: %w", err)
+	}
+	return StoredObject{Key: key}, nil
+}
+
+func (s *S3FileStore) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if url, ok := s.presignCache.get(key, s.refreshWindow); ok {
+		return url, nil
+	}
+
+	r, err := s.presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("couldn't presign get object: %w", err)
+	}
+
+	s.presignCache.set(key, r.URL, time.Now().Add(ttl))
+	return r.URL, nil
+}
+
+// Purge drops any cached presigned URL for key. Callers should invoke this
+// when key has been re-uploaded or deleted, since the old URL may now point
+// at stale or missing content.
+func (s *S3FileStore) Purge(key string) {
+	s.presignCache.purge(key)
+}
+
+func (s *S3FileStore) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't delete object: %w", err)
+	}
+	return nil
+}
+
+func (s *S3FileStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get object: %w", err)
+	}
+	return out.Body, nil
+}
+
+func (s *S3FileStore) CreateMultipartUpload(ctx context.Context, key, contentType string) (string, error) {
+	out, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("couldn't create multipart upload: %w", err)
+	}
+	return aws.ToString(out.UploadId), nil
+}
+
+func (s *S3FileStore) UploadPart(ctx context.Context, key, uploadID string, partNumber int32, body io.Reader) (string, error) {
+	out, err := s.client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(s.bucket),
+		Key:        aws.String(key),
+		UploadId:   aws.String(uploadID),
+		PartNumber: aws.Int32(partNumber),
+		Body:       body,
+	})
+	if err != nil {
+		return "", fmt.Errorf("couldn't upload part %d: %w", partNumber, err)
+	}
+	return aws.ToString(out.ETag), nil
+}
+
+func (s *S3FileStore) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart) (StoredObject, error) {
+	completed := make([]types.CompletedPart, len(parts))
+	for i, p := range parts {
+		completed[i] = types.CompletedPart{
+			PartNumber: aws.Int32(p.PartNumber),
+			ETag:       aws.String(p.ETag),
+		}
+	}
+	_, err := s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return StoredObject{}, fmt.Errorf("couldn't complete multipart upload: %w", err)
+	}
+	return StoredObject{Key: key}, nil
+}
+
+func (s *S3FileStore) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	_, err := s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't abort multipart upload: %w", err)
+	}
+	return nil
+}
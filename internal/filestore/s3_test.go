@@ -0,0 +1,52 @@
+package filestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+type fakePresignClient struct {
+	calls int
+}
+
+func (f *fakePresignClient) PresignGetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error) {
+	f.calls++
+	return &v4.PresignedHTTPRequest{URL: "https://example.com/signed"}, nil
+}
+
+func TestS3FileStorePresignGetCachesWithinRefreshWindow(t *testing.T) {
+	fake := &fakePresignClient{}
+	store := newS3FileStore(nil, fake, "bucket", time.Minute)
+
+	ctx := context.Background()
+	first, err := store.PresignGet(ctx, "videos/a.mp4", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("PresignGet returned error: %v", err)
+	}
+	if fake.calls != 1 {
+		t.Fatalf("calls after first PresignGet = %d, want 1", fake.calls)
+	}
+
+	second, err := store.PresignGet(ctx, "videos/a.mp4", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("PresignGet returned error: %v", err)
+	}
+	if fake.calls != 1 {
+		t.Errorf("calls after second PresignGet within window = %d, want 1 (cache should've been used)", fake.calls)
+	}
+	if second != first {
+		t.Errorf("second PresignGet = %q, want cached %q", second, first)
+	}
+
+	store.Purge("videos/a.mp4")
+	if _, err := store.PresignGet(ctx, "videos/a.mp4", 15*time.Minute); err != nil {
+		t.Fatalf("PresignGet returned error: %v", err)
+	}
+	if fake.calls != 2 {
+		t.Errorf("calls after Purge = %d, want 2 (cache should've been bypassed)", fake.calls)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/database"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/filestore"
+)
+
+// apiConfig bundles the dependencies every handler needs: the metadata DB,
+// the object store (S3 in production, disk for local dev), auth, and the
+// in-memory registries that back the progress/resumable-upload endpoints.
+type apiConfig struct {
+	db               *database.DB
+	fileStore        filestore.FileStore
+	jwtSecret        string
+	thumbnails       thumbnailConfig
+	presignExpiry    time.Duration
+	uploadSessions   *uploadSessionRegistry
+	multipartUploads *multipartUploadRegistry
+}
+
+func main() {
+	port := envOrDefault("PORT", "8091")
+	dbPath := envOrDefault("DB_PATH", "tubely.json")
+	jwtSecret := os.Getenv("JWT_SECRET")
+	if jwtSecret == "" {
+		log.Fatal("JWT_SECRET must be set")
+	}
+
+	db, err := database.NewDB(dbPath)
+	if err != nil {
+		log.Fatalf("couldn't open database at %s: %v", dbPath, err)
+	}
+
+	fileStore, err := newFileStore(context.Background())
+	if err != nil {
+		log.Fatalf("couldn't set up file store: %v", err)
+	}
+
+	cfg := &apiConfig{
+		db:               db,
+		fileStore:        fileStore,
+		jwtSecret:        jwtSecret,
+		thumbnails:       defaultThumbnailConfig,
+		presignExpiry:    defaultPresignExpiry,
+		uploadSessions:   newUploadSessionRegistry(),
+		multipartUploads: newMultipartUploadRegistry(),
+	}
+
+	mux := http.NewServeMux()
+	registerRoutes(mux, cfg)
+
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: mux,
+	}
+	log.Printf("serving on port %s", port)
+	log.Fatal(srv.ListenAndServe())
+}
+
+// newFileStore builds the FileStore implementation the server runs against:
+// S3 when S3_BUCKET is set (the production path), otherwise a DiskFileStore
+// rooted at ASSETS_ROOT so the app is runnable locally without AWS
+// credentials.
+func newFileStore(ctx context.Context) (filestore.FileStore, error) {
+	bucket := os.Getenv("S3_BUCKET")
+	if bucket == "" {
+		root := envOrDefault("ASSETS_ROOT", "./assets")
+		baseURL := envOrDefault("ASSETS_BASE_URL", "http://localhost:"+envOrDefault("PORT", "8091")+"/assets")
+		if err := os.MkdirAll(root, 0755); err != nil {
+			return nil, fmt.Errorf("couldn't create assets root %s: %w", root, err)
+		}
+		return filestore.NewDiskFileStore(root, baseURL), nil
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(os.Getenv("S3_REGION")))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load AWS config: %w", err)
+	}
+	client := s3.NewFromConfig(awsCfg)
+	return filestore.NewS3FileStore(client, bucket), nil
+}
+
+// registerRoutes wires every handler in this package onto mux. Path value
+// names (videoID, uploadID, partNumber) match what the handlers read via
+// r.PathValue.
+func registerRoutes(mux *http.ServeMux, cfg *apiConfig) {
+	mux.HandleFunc("POST /api/videos/{videoID}/upload", cfg.handlerUploadVideo)
+	mux.HandleFunc("POST /api/videos/{videoID}/thumbnail", cfg.handlerUploadThumbnail)
+	mux.HandleFunc("POST /api/videos/{videoID}/thumbnail/regenerate", cfg.handlerRegenerateThumbnail)
+	mux.HandleFunc("POST /api/videos/{videoID}/youtube_ingest", cfg.handlerIngestYoutube)
+
+	mux.HandleFunc("GET /api/video_upload/{uploadID}/progress", cfg.handlerVideoUploadProgress)
+
+	mux.HandleFunc("POST /api/video_uploads", cfg.handlerCreateVideoUpload)
+	mux.HandleFunc("PUT /api/video_uploads/{uploadID}/parts/{partNumber}", cfg.handlerUploadVideoPart)
+	mux.HandleFunc("GET /api/video_uploads/{uploadID}/parts", cfg.handlerListVideoUploadParts)
+	mux.HandleFunc("POST /api/video_uploads/{uploadID}/complete", cfg.handlerCompleteVideoUpload)
+	mux.HandleFunc("POST /api/video_uploads/{uploadID}/abort", cfg.handlerAbortVideoUpload)
+
+	if diskStore, ok := cfg.fileStore.(*filestore.DiskFileStore); ok {
+		mux.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(http.Dir(diskStore.Root()))))
+	}
+}
+
+func envOrDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
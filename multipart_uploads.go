@@ -0,0 +1,57 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// multipartUpload tracks the server-side state of one chunked video upload
+// in progress: which store-level upload it maps to. Uploaded part ETags
+// themselves live in cfg.db (see internal/database's UploadPart), not here,
+// so a client can resume after a dropped connection and ask which part
+// numbers it still needs to send. That resumability doesn't survive a
+// server restart, though: this struct - and the uploadID ->
+// store-upload-ID/key/owner mapping in multipartUploadRegistry below - is
+// in-memory only, so a restart leaves the persisted ETags orphaned with no
+// uploadID to look them up by.
+type multipartUpload struct {
+	videoID     uuid.UUID
+	userID      uuid.UUID
+	key         string
+	storeUpload string
+	mediaType   string
+}
+
+// multipartUploadRegistry is an in-memory store of chunked uploads keyed by
+// uploadID. Unlike uploadSessionRegistry it has no TTL cleanup: entries live
+// until the upload is completed or aborted.
+type multipartUploadRegistry struct {
+	mu      sync.Mutex
+	uploads map[string]*multipartUpload
+}
+
+func newMultipartUploadRegistry() *multipartUploadRegistry {
+	return &multipartUploadRegistry{uploads: make(map[string]*multipartUpload)}
+}
+
+func (r *multipartUploadRegistry) create(u *multipartUpload) string {
+	uploadID := uuid.NewString()
+	r.mu.Lock()
+	r.uploads[uploadID] = u
+	r.mu.Unlock()
+	return uploadID
+}
+
+func (r *multipartUploadRegistry) get(uploadID string) (*multipartUpload, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.uploads[uploadID]
+	return u, ok
+}
+
+func (r *multipartUploadRegistry) delete(uploadID string) {
+	r.mu.Lock()
+	delete(r.uploads, uploadID)
+	r.mu.Unlock()
+}
@@ -0,0 +1,30 @@
+package main
+
+import "io"
+
+// ProgressReader wraps an io.Reader whose total size is known up front and
+// reports how many bytes have passed through it so far via onProgress.
+type ProgressReader struct {
+	r          io.Reader
+	total      int64
+	read       int64
+	onProgress func(read, total int64)
+}
+
+// NewProgressReader wraps r, reporting progress against total bytes. onProgress
+// is called after every successful Read; it may be called many times and
+// should be cheap (e.g. pushing onto a buffered channel).
+func NewProgressReader(r io.Reader, total int64, onProgress func(read, total int64)) *ProgressReader {
+	return &ProgressReader{r: r, total: total, onProgress: onProgress}
+}
+
+func (p *ProgressReader) Read(buf []byte) (int, error) {
+	n, err := p.r.Read(buf)
+	if n > 0 {
+		p.read += int64(n)
+		if p.onProgress != nil {
+			p.onProgress(p.read, p.total)
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// defaultThumbnailTimestamp is the point in a video the auto-generated
+// poster frame is grabbed from when the caller doesn't ask for a specific
+// one.
+const defaultThumbnailTimestamp = "00:00:01"
+
+// thumbnailConfig controls the size and JPEG quality of generated poster
+// frames. The 177x100 default is a 16:9 frame (177/100 is about 16/9).
+type thumbnailConfig struct {
+	Width   int
+	Height  int
+	Quality int // ffmpeg -q:v, 2 (best) to 31 (worst)
+}
+
+var defaultThumbnailConfig = thumbnailConfig{Width: 177, Height: 100, Quality: 4}
+
+// generateThumbnailFrame grabs a single JPEG frame from videoFilePath at
+// timestamp and returns the path to it. If ffmpeg fails at that timestamp
+// (e.g. the video is shorter than it), it retries at 00:00:00.
+func generateThumbnailFrame(videoFilePath, timestamp string, cfg thumbnailConfig) (string, error) {
+	outputFilePath := videoFilePath + ".thumb.jpg"
+	vf := fmt.Sprintf(
+		"scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2",
+		cfg.Width, cfg.Height, cfg.Width, cfg.Height,
+	)
+
+	run := func(ts string) error {
+		os.Remove(outputFilePath)
+
+		cmd := exec.Command("ffmpeg", "-y",
+			"-ss", ts,
+			"-i", videoFilePath,
+			"-frames:v", "1",
+			"-vf", vf,
+			"-q:v", strconv.Itoa(cfg.Quality),
+			"-f", "image2",
+			outputFilePath,
+		)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			log.Println("Error:", err)
+			log.Println("Stderr:", stderr.String())
+			return err
+		}
+		// ffmpeg can exit 0 having written no frame at all, e.g. when -ss
+		// seeks past a short video's duration. Treat that the same as a
+		// non-zero exit: fall back to 00:00:00.
+		if _, err := os.Stat(outputFilePath); err != nil {
+			return fmt.Errorf("thumbnail wasn't produced: %w", err)
+		}
+		return nil
+	}
+
+	if err := run(timestamp); err != nil {
+		if timestamp == "00:00:00" {
+			return "", err
+		}
+		if err := run("00:00:00"); err != nil {
+			return "", err
+		}
+	}
+
+	return outputFilePath, nil
+}
+
+// generateAndStoreThumbnail grabs a poster frame from videoFilePath at
+// timestamp, uploads it through the same file store used by
+// handlerUploadThumbnail, and returns the key it was stored under.
+func (cfg *apiConfig) generateAndStoreThumbnail(ctx context.Context, videoFilePath, timestamp string) (string, error) {
+	thumbnailPath, err := generateThumbnailFrame(videoFilePath, timestamp, cfg.thumbnails)
+	if err != nil {
+		return "", fmt.Errorf("couldn't generate thumbnail: %w", err)
+	}
+	defer os.Remove(thumbnailPath)
+
+	thumbnailFile, err := os.Open(thumbnailPath)
+	if err != nil {
+		return "", fmt.Errorf("couldn't open generated thumbnail: %w", err)
+	}
+	defer thumbnailFile.Close()
+
+	thumbnailKey := fmt.Sprintf("%s.jpg", uuid.NewString())
+	if _, err := cfg.fileStore.Put(ctx, thumbnailKey, thumbnailFile, "image/jpeg"); err != nil {
+		return "", fmt.Errorf("couldn't store generated thumbnail: %w", err)
+	}
+	return thumbnailKey, nil
+}
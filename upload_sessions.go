@@ -0,0 +1,137 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// uploadStage identifies which phase of the video upload pipeline a
+// progress event belongs to.
+type uploadStage string
+
+const (
+	stageReceiving  uploadStage = "receiving"
+	stageProcessing uploadStage = "processing"
+	stageUploading  uploadStage = "uploading"
+)
+
+// uploadProgress is a single progress update, sent to clients as an SSE event.
+type uploadProgress struct {
+	Stage   uploadStage `json:"stage"`
+	Bytes   int64       `json:"bytes"`
+	Total   int64       `json:"total"`
+	Percent float64     `json:"percent"`
+}
+
+// uploadSession tracks one in-flight (or just-finished) video upload so the
+// progress endpoint can stream updates to a client that's polling on a
+// separate connection than the one doing the upload.
+type uploadSession struct {
+	userID     uuid.UUID
+	events     chan uploadProgress
+	done       chan struct{}
+	latest     uploadProgress
+	createdAt  time.Time
+	finishedAt time.Time
+	mu         sync.Mutex
+}
+
+// uploadSessionTTL is how long a finished session's final state stays
+// fetchable before the registry cleans it up.
+const uploadSessionTTL = 2 * time.Minute
+
+// uploadSessionRegistry is an in-memory store of upload sessions keyed by
+// uploadID, with background TTL cleanup. It's process-local, which is fine
+// since progress is best-effort UI feedback, not durable state.
+type uploadSessionRegistry struct {
+	mu       sync.Mutex
+	sessions map[string]*uploadSession
+}
+
+func newUploadSessionRegistry() *uploadSessionRegistry {
+	r := &uploadSessionRegistry{sessions: make(map[string]*uploadSession)}
+	go r.cleanupLoop()
+	return r
+}
+
+func (r *uploadSessionRegistry) create(uploadID string, userID uuid.UUID) *uploadSession {
+	s := &uploadSession{
+		userID:    userID,
+		events:    make(chan uploadProgress, 16),
+		done:      make(chan struct{}),
+		createdAt: time.Now(),
+	}
+	r.mu.Lock()
+	r.sessions[uploadID] = s
+	r.mu.Unlock()
+	return s
+}
+
+func (r *uploadSessionRegistry) get(uploadID string) (*uploadSession, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[uploadID]
+	return s, ok
+}
+
+func (r *uploadSessionRegistry) cleanupLoop() {
+	ticker := time.NewTicker(uploadSessionTTL)
+	defer ticker.Stop()
+	for range ticker.C {
+		cutoff := time.Now().Add(-uploadSessionTTL)
+		r.mu.Lock()
+		for id, s := range r.sessions {
+			// Only expire sessions that have actually finished: a
+			// multi-hundred-MB upload can easily take longer than the TTL to
+			// receive, process, and store, and it must keep answering
+			// progress polls the whole time it's still running.
+			if finishedAt := s.finishTime(); !finishedAt.IsZero() && finishedAt.Before(cutoff) {
+				delete(r.sessions, id)
+			}
+		}
+		r.mu.Unlock()
+	}
+}
+
+// report records a progress update and makes it available both to anyone
+// currently reading s.events and to anyone who calls latestProgress later.
+func (s *uploadSession) report(stage uploadStage, bytes, total int64) {
+	percent := 0.0
+	if total > 0 {
+		percent = float64(bytes) / float64(total) * 100
+	}
+	p := uploadProgress{Stage: stage, Bytes: bytes, Total: total, Percent: percent}
+
+	s.mu.Lock()
+	s.latest = p
+	s.mu.Unlock()
+
+	select {
+	case s.events <- p:
+	default:
+		// Slow or absent reader: drop the update, latest is still available.
+	}
+}
+
+func (s *uploadSession) finish() {
+	s.mu.Lock()
+	s.finishedAt = time.Now()
+	s.mu.Unlock()
+	close(s.done)
+}
+
+// finishTime returns when the session finished, or the zero Time if it's
+// still in progress.
+func (s *uploadSession) finishTime() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.finishedAt
+}
+
+func (s *uploadSession) latestProgress() uploadProgress {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latest
+}